@@ -0,0 +1,107 @@
+package chartvendor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	gwErrors "github.com/gruntwork-io/gruntwork-cli/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gruntwork-io/terratest/modules/shell"
+)
+
+// InitChartfile creates an empty Chartfile in dir, ready for AddChart calls. It fails the test if a Chartfile
+// already exists at that location.
+func InitChartfile(t *testing.T, dir string) string {
+	path := filepath.Join(dir, "Chartfile")
+	if _, err := os.Stat(path); err == nil {
+		t.Fatalf("a Chartfile already exists at %s", path)
+	}
+
+	chartfile := &Chartfile{Directory: "charts"}
+	require.NoError(t, saveChartfileE(path, chartfile))
+	return path
+}
+
+// AddChart appends ref to the Chartfile at chartfilePath and persists it. It fails the test if the Chartfile
+// cannot be read or written.
+func AddChart(t *testing.T, chartfilePath string, ref ChartRef) {
+	chartfile, err := loadChartfileE(chartfilePath)
+	require.NoError(t, err)
+
+	chartfile.Charts = append(chartfile.Charts, ref)
+	require.NoError(t, saveChartfileE(chartfilePath, chartfile))
+}
+
+// VendorCharts resolves every chart declared in the Chartfile at chartfilePath and materializes them into the
+// Chartfile's target directory, so RenderTemplateE always has a hermetic, version-pinned chart directory available.
+// It fails the test if any chart cannot be resolved.
+func VendorCharts(t *testing.T, chartfilePath string) {
+	require.NoError(t, VendorChartsE(t, chartfilePath))
+}
+
+// VendorChartsE is the same as VendorCharts, but returns an error instead of failing the test.
+func VendorChartsE(t *testing.T, chartfilePath string) error {
+	chartfile, err := loadChartfileE(chartfilePath)
+	if err != nil {
+		return err
+	}
+
+	targetDir := chartfile.resolvedDir(chartfilePath)
+	if err := os.MkdirAll(targetDir, 0755); err != nil {
+		return gwErrors.WithStackTrace(err)
+	}
+
+	for _, ref := range chartfile.Charts {
+		if err := pullChartE(t, targetDir, ref); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pullChartE pulls and untars a single chart dependency into targetDir, mirroring `helm pull --untar`. If
+// ref.Verify is set, it also passes `--verify` (and `--keyring`, if ref.Keyring is set), requiring the repository
+// to publish a `.prov` file and failing the pull if the chart's provenance can't be verified.
+func pullChartE(t *testing.T, targetDir string, ref ChartRef) error {
+	name := ref.Alias
+	if name == "" {
+		name = ref.Chart
+	}
+	destDir := filepath.Join(targetDir, name)
+	if err := os.RemoveAll(destDir); err != nil {
+		return gwErrors.WithStackTrace(err)
+	}
+
+	args := []string{
+		"pull", fmt.Sprintf("%s/%s", ref.Repository, ref.Chart),
+		"--version", ref.Version,
+		"--untar",
+		"--untardir", targetDir,
+		"--destination", targetDir,
+	}
+	if ref.Verify {
+		args = append(args, "--verify")
+		if ref.Keyring != "" {
+			args = append(args, "--keyring", ref.Keyring)
+		}
+	}
+
+	_, err := shell.RunCommandAndGetOutputE(t, shell.Command{
+		Command: "helm",
+		Args:    args,
+	})
+	if err != nil {
+		return gwErrors.WithStackTrace(err)
+	}
+
+	if ref.Alias != "" && ref.Alias != ref.Chart {
+		if err := os.Rename(filepath.Join(targetDir, ref.Chart), destDir); err != nil {
+			return gwErrors.WithStackTrace(err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package helm
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"testing"
+	"text/template"
+
+	gwErrors "github.com/gruntwork-io/gruntwork-cli/errors"
+)
+
+// templateValuesFilesE renders each of options.ValuesFiles as a Go text/template, exposing `.Environment.Name`,
+// `.Environment.Values`, and `.Values` (options.SetValues, for convenience) to the template, and writes the
+// interpolated result to a temp file. It returns the list of paths to pass to helm via `-f` in place of the
+// original ValuesFiles. If options.Environment is nil, the original ValuesFiles are returned unchanged.
+func templateValuesFilesE(t *testing.T, options *Options) ([]string, error) {
+	if options.Environment == nil {
+		return options.ValuesFiles, nil
+	}
+
+	data := struct {
+		Environment *Environment
+		Values      map[string]string
+	}{
+		Environment: options.Environment,
+		Values:      options.SetValues,
+	}
+
+	rendered := make([]string, 0, len(options.ValuesFiles))
+	for _, valuesFile := range options.ValuesFiles {
+		tmpl, err := template.ParseFiles(valuesFile)
+		if err != nil {
+			return nil, gwErrors.WithStackTrace(err)
+		}
+
+		tmpFile, err := ioutil.TempFile("", "terratest-helm-values-*.yaml")
+		if err != nil {
+			return nil, gwErrors.WithStackTrace(err)
+		}
+		t.Cleanup(func() { os.Remove(tmpFile.Name()) })
+
+		err = tmpl.Execute(tmpFile, data)
+		tmpFile.Close()
+		if err != nil {
+			return nil, gwErrors.WithStackTrace(err)
+		}
+
+		rendered = append(rendered, tmpFile.Name())
+	}
+
+	return rendered, nil
+}
+
+// stateValuesSetArgs turns options.StateValuesSet into "--set" args, one per entry, in sorted key order for
+// deterministic command lines. Each key is passed straight through to helm as-is (e.g. "a.b.c"), which is where the
+// actual dotted-key merge happens - `helm --set a.b.c=x --set a.b.d=y` already merges siblings under "a.b"
+// correctly, so there is no flatten/merge step to do on our side.
+func stateValuesSetArgs(options *Options) []string {
+	if len(options.StateValuesSet) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(options.StateValuesSet))
+	for key := range options.StateValuesSet {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	args := make([]string, 0, len(keys)*2)
+	for _, key := range keys {
+		args = append(args, "--set", fmt.Sprintf("%s=%v", key, options.StateValuesSet[key]))
+	}
+	return args
+}
@@ -0,0 +1,53 @@
+package helm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStateValuesSetArgs(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		options  *Options
+		expected []string
+	}{
+		{
+			name:     "empty StateValuesSet produces no args",
+			options:  &Options{},
+			expected: nil,
+		},
+		{
+			name: "each entry becomes a --set arg in sorted key order",
+			options: &Options{
+				StateValuesSet: map[string]interface{}{
+					"b.key": "second",
+					"a.key": "first",
+				},
+			},
+			expected: []string{"--set", "a.key=first", "--set", "b.key=second"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, testCase.expected, stateValuesSetArgs(testCase.options))
+		})
+	}
+}
+
+func TestTemplateValuesFilesENoEnvironment(t *testing.T) {
+	t.Parallel()
+
+	options := &Options{ValuesFiles: []string{"values.yaml", "values-override.yaml"}}
+
+	rendered, err := templateValuesFilesE(t, options)
+
+	assert.NoError(t, err)
+	assert.Equal(t, options.ValuesFiles, rendered)
+}
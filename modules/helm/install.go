@@ -0,0 +1,51 @@
+package helm
+
+import (
+	"testing"
+
+	gwErrors "github.com/gruntwork-io/gruntwork-cli/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gruntwork-io/terratest/modules/files"
+)
+
+// Install runs `helm install` with the given options and chart, failing the test if there is an error.
+func Install(t *testing.T, options *Options, chartDir string, releaseName string) string {
+	out, err := InstallE(t, options, chartDir, releaseName)
+	require.NoError(t, err)
+	return out
+}
+
+// InstallE runs `helm install` with the given options and chart. Before installing, it `helm repo add`s and
+// `helm repo update`s every entry in options.Repositories, so the chart (or any of its dependencies) can be
+// resolved from a repo the test just stood up, e.g. via repotest.StartChartRepo.
+func InstallE(t *testing.T, options *Options, chartDir string, releaseName string) (string, error) {
+	if !files.FileExists(chartDir) {
+		return "", gwErrors.WithStackTrace(ChartNotFoundError{chartDir})
+	}
+
+	if err := addRepositoriesE(t, options); err != nil {
+		return "", err
+	}
+
+	renderedValuesFiles, err := templateValuesFilesE(t, options)
+	if err != nil {
+		return "", err
+	}
+	installOptions := *options
+	installOptions.ValuesFiles = renderedValuesFiles
+	options = &installOptions
+
+	args := []string{releaseName, chartDir}
+	if options.KubectlOptions != nil && options.KubectlOptions.Namespace != "" {
+		args = append(args, "--namespace", options.KubectlOptions.Namespace)
+	}
+
+	args, err = getValuesArgsE(t, options, args...)
+	if err != nil {
+		return "", err
+	}
+	args = append(args, stateValuesSetArgs(options)...)
+
+	return RunHelmCommandAndGetOutputE(t, options, "install", args...)
+}
@@ -0,0 +1,144 @@
+package helm
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"testing"
+
+	gwErrors "github.com/gruntwork-io/gruntwork-cli/errors"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+var extraSchemeBuilders []func(*runtime.Scheme) error
+
+// RegisterScheme registers an additional `AddToScheme` function (as generated by client-gen for a CRD's Go types)
+// with the scheme used by UnmarshalK8SYamlAllE, so rendered CRD instances decode into their typed Go structs
+// instead of being left as unstructured objects.
+func RegisterScheme(addToScheme func(*runtime.Scheme) error) {
+	extraSchemeBuilders = append(extraSchemeBuilders, addToScheme)
+}
+
+// UnmarshalK8SYamlAll is the same as UnmarshalK8SYamlAllE, but will fail the test if there is an error.
+func UnmarshalK8SYamlAll(t *testing.T, yamlData string) []runtime.Object {
+	objs, err := UnmarshalK8SYamlAllE(t, yamlData)
+	require.NoError(t, err)
+	return objs
+}
+
+// UnmarshalK8SYamlAllE splits yamlData on `---` document boundaries and decodes each document into the
+// corresponding typed client-go struct (e.g. a Deployment doc becomes an *appsv1.Deployment), using a scheme built
+// from kubernetes/scheme plus any schemes registered via RegisterScheme. Use RenderedManifests to get a more
+// ergonomic view over the result, e.g. `helm.AsRenderedManifests(objs).Deployments()`.
+//
+// Each call builds its own fresh scheme rather than mutating the process-global scheme.Scheme. Functions passed to
+// RegisterScheme are kept in a package-level list, though, so they apply to every later call in the same process,
+// including calls from other tests sharing the same test binary.
+func UnmarshalK8SYamlAllE(t *testing.T, yamlData string) ([]runtime.Object, error) {
+	decodeScheme := runtime.NewScheme()
+	if err := scheme.AddToScheme(decodeScheme); err != nil {
+		return nil, gwErrors.WithStackTrace(err)
+	}
+	for _, addToScheme := range extraSchemeBuilders {
+		if err := addToScheme(decodeScheme); err != nil {
+			return nil, gwErrors.WithStackTrace(err)
+		}
+	}
+	decoder := serializer.NewCodecFactory(decodeScheme).UniversalDeserializer()
+
+	var objs []runtime.Object
+	reader := yamlDocReader(yamlData)
+	for {
+		doc, err := reader()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, gwErrors.WithStackTrace(err)
+		}
+		if len(bytes.TrimSpace(doc)) == 0 {
+			continue
+		}
+
+		obj, _, err := decoder.Decode(doc, nil, nil)
+		if err != nil {
+			return nil, gwErrors.WithStackTrace(err)
+		}
+		objs = append(objs, obj)
+	}
+
+	return objs, nil
+}
+
+// yamlDocReader returns a function that yields successive `---`-delimited YAML documents from data, and io.EOF
+// once exhausted.
+func yamlDocReader(data string) func() ([]byte, error) {
+	scanner := bufio.NewScanner(bytes.NewReader([]byte(data)))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var buf bytes.Buffer
+	done := false
+
+	return func() ([]byte, error) {
+		if done {
+			return nil, io.EOF
+		}
+		buf.Reset()
+		for scanner.Scan() {
+			line := scanner.Text()
+			if bytes.Equal(bytes.TrimSpace([]byte(line)), []byte("---")) {
+				return buf.Bytes(), nil
+			}
+			buf.WriteString(line)
+			buf.WriteString("\n")
+		}
+		done = true
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+}
+
+// FilterByKind returns only the elements of objs whose concrete type is T, e.g.
+// FilterByKind[*appsv1.Deployment](objs).
+func FilterByKind[T runtime.Object](objs []runtime.Object) []T {
+	var filtered []T
+	for _, obj := range objs {
+		if typed, ok := obj.(T); ok {
+			filtered = append(filtered, typed)
+		}
+	}
+	return filtered
+}
+
+// RenderedManifests wraps the typed objects decoded from a rendered chart, providing ergonomic accessors for the
+// most commonly asserted-on kinds.
+type RenderedManifests struct {
+	Objects []runtime.Object
+}
+
+// AsRenderedManifests wraps objs (as returned by UnmarshalK8SYamlAllE) in a RenderedManifests.
+func AsRenderedManifests(objs []runtime.Object) RenderedManifests {
+	return RenderedManifests{Objects: objs}
+}
+
+// Deployments returns every *appsv1.Deployment decoded from the manifests.
+func (r RenderedManifests) Deployments() []*appsv1.Deployment {
+	return FilterByKind[*appsv1.Deployment](r.Objects)
+}
+
+// Services returns every *corev1.Service decoded from the manifests.
+func (r RenderedManifests) Services() []*corev1.Service {
+	return FilterByKind[*corev1.Service](r.Objects)
+}
+
+// ConfigMaps returns every *corev1.ConfigMap decoded from the manifests.
+func (r RenderedManifests) ConfigMaps() []*corev1.ConfigMap {
+	return FilterByKind[*corev1.ConfigMap](r.Objects)
+}
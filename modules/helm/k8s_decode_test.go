@@ -0,0 +1,41 @@
+package helm
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestYamlDocReader(t *testing.T) {
+	t.Parallel()
+
+	data := "kind: Deployment\nname: foo\n---\nkind: Service\nname: bar\n"
+
+	reader := yamlDocReader(data)
+
+	doc1, err := reader()
+	require.NoError(t, err)
+	assert.Equal(t, "kind: Deployment\nname: foo\n", string(doc1))
+
+	doc2, err := reader()
+	require.NoError(t, err)
+	assert.Equal(t, "kind: Service\nname: bar\n", string(doc2))
+
+	_, err = reader()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestYamlDocReaderEmptyInput(t *testing.T) {
+	t.Parallel()
+
+	reader := yamlDocReader("")
+
+	doc, err := reader()
+	require.NoError(t, err)
+	assert.Empty(t, string(doc))
+
+	_, err = reader()
+	assert.Equal(t, io.EOF, err)
+}
@@ -0,0 +1,51 @@
+package helm
+
+import (
+	"github.com/gruntwork-io/terratest/modules/k8s"
+)
+
+// Options defines the options that are passed to Helm commands from the corresponding Helm Options methods in this
+// package.
+type Options struct {
+	KubectlOptions *k8s.KubectlOptions
+
+	// ValuesFiles is a list of values files to pass to helm via `-f`, in the order given.
+	ValuesFiles []string
+
+	// SetValues is a list of values to set via `--set` on the helm command line, where the key can be a dotted
+	// path to set a nested value.
+	SetValues map[string]string
+
+	// SetStrValues is the same as SetValues, but passed via `--set-string`, forcing the value to be interpreted
+	// as a string instead of helm's usual type inference.
+	SetStrValues map[string]string
+
+	// Environment, if set, renders ValuesFiles as Go templates before passing them to helm, exposing
+	// `.Environment.Name`, `.Environment.Values`, and `.Values` to the template.
+	Environment *Environment
+
+	// StateValuesSet is set via repeated `--set` flags after ValuesFiles and SetValues/SetStrValues are applied,
+	// using dotted keys that are merged into any existing nested maps rather than overwriting the first child.
+	StateValuesSet map[string]interface{}
+
+	// Repositories lists chart repositories that should be `helm repo add`ed (and `helm repo update`d) before
+	// rendering or installing, so tests can depend on charts living in a repo (e.g. one started with
+	// repotest.StartChartRepo) without shelling out to `helm repo add` themselves.
+	Repositories []RepoEntry
+
+	ExtraArgs map[string][]string
+	EnvVars   map[string]string
+}
+
+// RepoEntry is a single `helm repo add <Name> <URL>` entry.
+type RepoEntry struct {
+	Name string
+	URL  string
+}
+
+// Environment names a deployment environment (e.g. dev/staging/prod) along with the values that should be made
+// available to templated values files rendered under that environment.
+type Environment struct {
+	Name   string
+	Values map[string]interface{}
+}
@@ -0,0 +1,26 @@
+package helm
+
+import (
+	"testing"
+
+	gwErrors "github.com/gruntwork-io/gruntwork-cli/errors"
+)
+
+// addRepositoriesE runs `helm repo add` for each entry in options.Repositories, followed by a single
+// `helm repo update`, so dependency resolution can see them. It is a no-op if options.Repositories is empty.
+func addRepositoriesE(t *testing.T, options *Options) error {
+	if len(options.Repositories) == 0 {
+		return nil
+	}
+
+	for _, repository := range options.Repositories {
+		if _, err := RunHelmCommandAndGetOutputE(t, options, "repo", "add", repository.Name, repository.URL); err != nil {
+			return gwErrors.WithStackTrace(err)
+		}
+	}
+
+	if _, err := RunHelmCommandAndGetOutputE(t, options, "repo", "update"); err != nil {
+		return gwErrors.WithStackTrace(err)
+	}
+	return nil
+}
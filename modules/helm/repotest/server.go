@@ -0,0 +1,186 @@
+// Package repotest spins up an in-process, ephemeral Helm chart repository server so integration tests can exercise
+// dependency resolution, `helm search repo`, and pull-by-version flows entirely offline.
+package repotest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/ghodss/yaml"
+	gwErrors "github.com/gruntwork-io/gruntwork-cli/errors"
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/provenance"
+	"helm.sh/helm/v3/pkg/repo"
+	helmtime "helm.sh/helm/v3/pkg/time"
+)
+
+// ChartRepoServer is an in-process chart repository: it serves index.yaml and chart tarballs, plus the
+// Chartmuseum/Harbor-style `/api/chartrepo/{repo}/charts` upload, list, and delete endpoints, on an ephemeral port.
+type ChartRepoServer struct {
+	t        *testing.T
+	server   *httptest.Server
+	storeDir string
+	repoName string
+
+	mu    sync.Mutex
+	index *repo.IndexFile
+}
+
+// StartChartRepo starts a new ChartRepoServer backed by a temp directory, named "testrepo". It is automatically
+// closed, and its backing storage removed, via t.Cleanup.
+func StartChartRepo(t *testing.T) *ChartRepoServer {
+	storeDir, err := ioutil.TempDir("", "terratest-chart-repo")
+	require.NoError(t, err)
+
+	s := &ChartRepoServer{
+		t:        t,
+		storeDir: storeDir,
+		repoName: "testrepo",
+		index:    repo.NewIndexFile(),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/index.yaml", s.handleIndex)
+	mux.HandleFunc("/charts/", s.handleGetChart)
+	mux.HandleFunc(fmt.Sprintf("/api/chartrepo/%s/charts", s.repoName), s.handleChartsAPI)
+	mux.HandleFunc(fmt.Sprintf("/api/chartrepo/%s/charts/", s.repoName), s.handleChartsAPIByName)
+
+	s.server = httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+	return s
+}
+
+// URL returns the base URL of the running repo server, suitable for `helm repo add`.
+func (s *ChartRepoServer) URL() string {
+	return s.server.URL
+}
+
+// Close shuts down the server and removes its backing storage directory.
+func (s *ChartRepoServer) Close() {
+	s.server.Close()
+	os.RemoveAll(s.storeDir)
+}
+
+// PushChart packages the chart at chartDir and publishes it to the repo, regenerating index.yaml. It fails the
+// test if the chart cannot be packaged.
+func (s *ChartRepoServer) PushChart(chartDir string) {
+	require.NoError(s.t, s.PushChartE(chartDir))
+}
+
+// PushChartE is the same as PushChart, but returns an error instead of failing the test.
+func (s *ChartRepoServer) PushChartE(chartDir string) error {
+	chrt, err := loader.Load(chartDir)
+	if err != nil {
+		return gwErrors.WithStackTrace(err)
+	}
+
+	tarballPath, err := chartutil.Save(chrt, s.storeDir)
+	if err != nil {
+		return gwErrors.WithStackTrace(err)
+	}
+
+	return s.addToIndexE(chrt.Metadata, filepath.Base(tarballPath))
+}
+
+func (s *ChartRepoServer) addToIndexE(meta *chart.Metadata, tarballName string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	digest, err := provenance.DigestFile(filepath.Join(s.storeDir, tarballName))
+	if err != nil {
+		return gwErrors.WithStackTrace(err)
+	}
+
+	entry := &repo.ChartVersion{
+		Metadata: meta,
+		URLs:     []string{fmt.Sprintf("%s/charts/%s", s.server.URL, tarballName)},
+		Digest:   digest,
+		Created:  helmtime.Now(),
+	}
+
+	s.index.Entries[meta.Name] = append(s.index.Entries[meta.Name], entry)
+	s.index.SortEntries()
+	s.index.Generated = helmtime.Now()
+	return nil
+}
+
+func (s *ChartRepoServer) handleIndex(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	raw, err := yaml.Marshal(s.index)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/x-yaml")
+	w.Write(raw)
+}
+
+func (s *ChartRepoServer) handleGetChart(w http.ResponseWriter, r *http.Request) {
+	name := filepath.Base(r.URL.Path)
+	http.ServeFile(w, r, filepath.Join(s.storeDir, name))
+}
+
+// handleChartsAPI implements the Chartmuseum/Harbor-style list (GET) and upload (POST) endpoints at
+// `/api/chartrepo/{repo}/charts`.
+func (s *ChartRepoServer) handleChartsAPI(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		json.NewEncoder(w).Encode(s.index.Entries)
+	case http.MethodPost:
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		chrt, err := loader.LoadArchive(bytes.NewReader(body))
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid chart archive: %s", err), http.StatusBadRequest)
+			return
+		}
+
+		tarballName := fmt.Sprintf("%s-%s.tgz", chrt.Metadata.Name, chrt.Metadata.Version)
+		if err := ioutil.WriteFile(filepath.Join(s.storeDir, tarballName), body, 0644); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		if err := s.addToIndexE(chrt.Metadata, tarballName); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleChartsAPIByName implements the delete-by-name endpoint at `/api/chartrepo/{repo}/charts/{name}`.
+func (s *ChartRepoServer) handleChartsAPIByName(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	name := filepath.Base(r.URL.Path)
+	delete(s.index.Entries, name)
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,196 @@
+package helm
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/ghodss/yaml"
+	gwErrors "github.com/gruntwork-io/gruntwork-cli/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gruntwork-io/terratest/modules/files"
+)
+
+// Normalizer mutates a decoded YAML document in place to strip non-deterministic fields (timestamps, generated
+// hashes, random suffixes, etc.) before it is compared against a stored snapshot.
+type Normalizer func(obj map[string]interface{})
+
+// TERRATEST_UPDATE_SNAPSHOTS_ENV_VAR is the environment variable that, when set to "1", causes
+// RequireTemplateMatchesSnapshotE to (re)write the snapshot file instead of comparing against it.
+const TERRATEST_UPDATE_SNAPSHOTS_ENV_VAR = "TERRATEST_UPDATE_SNAPSHOTS"
+
+// RequireTemplateMatchesSnapshot renders the given chart and fails the test, with a per-document, per-field diff,
+// if the result doesn't match the golden file at snapshotPath. See RequireTemplateMatchesSnapshotE for details.
+func RequireTemplateMatchesSnapshot(t *testing.T, options *Options, chartDir string, releaseName string, templateFiles []string, snapshotPath string, normalizers []Normalizer) {
+	require.NoError(t, RequireTemplateMatchesSnapshotE(t, options, chartDir, releaseName, templateFiles, snapshotPath, normalizers))
+}
+
+// RequireTemplateMatchesSnapshotE renders the template given the provided options, normalizes non-deterministic
+// fields via normalizers, and diffs the result against the golden YAML file at snapshotPath.
+//
+// If snapshotPath does not exist, or the TERRATEST_UPDATE_SNAPSHOTS environment variable is set to "1", the
+// rendered (normalized) output is written to snapshotPath and no comparison is made. Otherwise, the rendered
+// output is compared document-by-document and field-by-field against the golden file, so that key reordering
+// doesn't produce noise, and a SnapshotMismatchError listing every difference found is returned.
+func RequireTemplateMatchesSnapshotE(t *testing.T, options *Options, chartDir string, releaseName string, templateFiles []string, snapshotPath string, normalizers []Normalizer) error {
+	rendered, err := RenderTemplateE(t, options, chartDir, releaseName, templateFiles, HELM_V3)
+	if err != nil {
+		return err
+	}
+
+	normalized, err := normalizeManifestE(rendered, normalizers)
+	if err != nil {
+		return err
+	}
+
+	if os.Getenv(TERRATEST_UPDATE_SNAPSHOTS_ENV_VAR) == "1" || !files.FileExists(snapshotPath) {
+		return writeSnapshotE(snapshotPath, normalized)
+	}
+
+	golden, err := readSnapshotE(snapshotPath)
+	if err != nil {
+		return err
+	}
+
+	return diffSnapshotsE(golden, normalized)
+}
+
+func normalizeManifestE(rendered string, normalizers []Normalizer) ([]map[string]interface{}, error) {
+	docs, err := splitYamlDocumentsE(rendered)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, doc := range docs {
+		for _, normalize := range normalizers {
+			normalize(doc)
+		}
+	}
+	return docs, nil
+}
+
+func splitYamlDocumentsE(rendered string) ([]map[string]interface{}, error) {
+	var docs []map[string]interface{}
+	reader := yamlDocReader(rendered)
+	for {
+		raw, err := reader()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, gwErrors.WithStackTrace(err)
+		}
+		if len(raw) == 0 {
+			continue
+		}
+
+		var doc map[string]interface{}
+		if err := yaml.Unmarshal(raw, &doc); err != nil {
+			return nil, gwErrors.WithStackTrace(err)
+		}
+		if doc != nil {
+			docs = append(docs, doc)
+		}
+	}
+	return docs, nil
+}
+
+func writeSnapshotE(snapshotPath string, docs []map[string]interface{}) error {
+	raw, err := yaml.Marshal(docs)
+	if err != nil {
+		return gwErrors.WithStackTrace(err)
+	}
+	if err := os.WriteFile(snapshotPath, raw, 0644); err != nil {
+		return gwErrors.WithStackTrace(err)
+	}
+	return nil
+}
+
+func readSnapshotE(snapshotPath string) ([]map[string]interface{}, error) {
+	raw, err := os.ReadFile(snapshotPath)
+	if err != nil {
+		return nil, gwErrors.WithStackTrace(err)
+	}
+
+	var docs []map[string]interface{}
+	if err := yaml.Unmarshal(raw, &docs); err != nil {
+		return nil, gwErrors.WithStackTrace(err)
+	}
+	return docs, nil
+}
+
+// diffSnapshotsE compares golden against actual document-by-document and field-by-field, returning a
+// SnapshotMismatchError describing every difference found, or nil if they match.
+func diffSnapshotsE(golden, actual []map[string]interface{}) error {
+	var diffs []string
+
+	max := len(golden)
+	if len(actual) > max {
+		max = len(actual)
+	}
+
+	for i := 0; i < max; i++ {
+		if i >= len(golden) {
+			diffs = append(diffs, fmt.Sprintf("document %d: present in rendered output but missing from snapshot", i))
+			continue
+		}
+		if i >= len(actual) {
+			diffs = append(diffs, fmt.Sprintf("document %d: present in snapshot but missing from rendered output", i))
+			continue
+		}
+		diffs = append(diffs, diffFields(fmt.Sprintf("document %d", i), golden[i], actual[i])...)
+	}
+
+	if len(diffs) == 0 {
+		return nil
+	}
+	return SnapshotMismatchError{Diffs: diffs}
+}
+
+func diffFields(path string, golden, actual map[string]interface{}) []string {
+	var diffs []string
+
+	for key, goldenVal := range golden {
+		actualVal, ok := actual[key]
+		if !ok {
+			diffs = append(diffs, fmt.Sprintf("%s.%s: missing from rendered output (expected %v)", path, key, goldenVal))
+			continue
+		}
+		diffs = append(diffs, diffValue(fmt.Sprintf("%s.%s", path, key), goldenVal, actualVal)...)
+	}
+	for key, actualVal := range actual {
+		if _, ok := golden[key]; !ok {
+			diffs = append(diffs, fmt.Sprintf("%s.%s: unexpected field in rendered output (got %v)", path, key, actualVal))
+		}
+	}
+
+	return diffs
+}
+
+func diffValue(path string, golden, actual interface{}) []string {
+	goldenMap, goldenIsMap := golden.(map[string]interface{})
+	actualMap, actualIsMap := actual.(map[string]interface{})
+	if goldenIsMap && actualIsMap {
+		return diffFields(path, goldenMap, actualMap)
+	}
+
+	if fmt.Sprintf("%v", golden) != fmt.Sprintf("%v", actual) {
+		return []string{fmt.Sprintf("%s: expected %v, got %v", path, golden, actual)}
+	}
+	return nil
+}
+
+// SnapshotMismatchError is returned when rendered output doesn't match a stored snapshot.
+type SnapshotMismatchError struct {
+	Diffs []string
+}
+
+func (e SnapshotMismatchError) Error() string {
+	msg := "rendered template does not match snapshot:\n"
+	for _, diff := range e.Diffs {
+		msg += "  " + diff + "\n"
+	}
+	return msg
+}
@@ -0,0 +1,71 @@
+package helm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffFields(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		golden   map[string]interface{}
+		actual   map[string]interface{}
+		expected []string
+	}{
+		{
+			name:     "identical maps produce no diffs",
+			golden:   map[string]interface{}{"a": "1", "b": "2"},
+			actual:   map[string]interface{}{"a": "1", "b": "2"},
+			expected: nil,
+		},
+		{
+			name:     "changed field is reported",
+			golden:   map[string]interface{}{"a": "1"},
+			actual:   map[string]interface{}{"a": "2"},
+			expected: []string{"doc.a: expected 1, got 2"},
+		},
+		{
+			name:     "missing field is reported",
+			golden:   map[string]interface{}{"a": "1"},
+			actual:   map[string]interface{}{},
+			expected: []string{"doc.a: missing from rendered output (expected 1)"},
+		},
+		{
+			name:     "unexpected field is reported",
+			golden:   map[string]interface{}{},
+			actual:   map[string]interface{}{"a": "1"},
+			expected: []string{"doc.a: unexpected field in rendered output (got 1)"},
+		},
+		{
+			name:     "nested maps are compared regardless of key order",
+			golden:   map[string]interface{}{"spec": map[string]interface{}{"x": "1", "y": "2"}},
+			actual:   map[string]interface{}{"spec": map[string]interface{}{"y": "2", "x": "1"}},
+			expected: nil,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, testCase.expected, diffFields("doc", testCase.golden, testCase.actual))
+		})
+	}
+}
+
+func TestDiffSnapshotsE(t *testing.T) {
+	t.Parallel()
+
+	golden := []map[string]interface{}{{"kind": "Deployment"}}
+	actual := []map[string]interface{}{{"kind": "Deployment"}}
+
+	assert.NoError(t, diffSnapshotsE(golden, actual))
+
+	actual = []map[string]interface{}{{"kind": "Service"}}
+	err := diffSnapshotsE(golden, actual)
+	assert.Error(t, err)
+}
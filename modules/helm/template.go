@@ -58,6 +58,22 @@ func RenderTemplateE(t *testing.T, options *Options, chartDir string, releaseNam
 		return "", gwErrors.WithStackTrace(ChartNotFoundError{chartDir})
 	}
 
+	if err := addRepositoriesE(t, options); err != nil {
+		return "", err
+	}
+
+	// If an Environment is set, render ValuesFiles as Go templates (exposing .Environment and .Values) before
+	// handing them to helm, so the same chart can be rendered under multiple named environments. This is done
+	// against a local copy of options so that re-rendering the same *Options under a different Environment keeps
+	// working - mutating options.ValuesFiles in place would leave it pointing at the first render's temp files.
+	renderedValuesFiles, err := templateValuesFilesE(t, options)
+	if err != nil {
+		return "", err
+	}
+	renderOptions := *options
+	renderOptions.ValuesFiles = renderedValuesFiles
+	options = &renderOptions
+
 	if helmVersion == HELM_V2 {
 		args, err = getHelm2Args(releaseName, options, t, templateFiles, chartDir)
 	}
@@ -65,6 +81,14 @@ func RenderTemplateE(t *testing.T, options *Options, chartDir string, releaseNam
 	if helmVersion == HELM_V3 {
 		args, err = getHelm3Args(releaseName, options, t, templateFiles, chartDir)
 	}
+	if err != nil {
+		return "", err
+	}
+
+	// StateValuesSet is applied last, after ValuesFiles and SetValues/SetStrValues, merging into existing nested
+	// maps rather than overwriting the first child.
+	args = append(args, stateValuesSetArgs(options)...)
+
 	// Finally, call out to helm template command
 	return RunHelmCommandAndGetOutputE(t, options, "template", args...)
 }
@@ -0,0 +1,236 @@
+package helm
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	gwErrors "github.com/gruntwork-io/gruntwork-cli/errors"
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/strvals"
+
+	"github.com/gruntwork-io/terratest/modules/files"
+)
+
+// RenderTemplateWithSDK runs the Helm SDK's Install.Run (with ClientOnly/DryRun set) to render the template given the
+// provided options and returns the rendered manifests concatenated into a single string. If you pass in
+// templateFiles, this will only render those templates. This function will fail the test if there is an error
+// rendering the template.
+func RenderTemplateWithSDK(t *testing.T, options *Options, chartDir string, releaseName string, templateFiles []string) string {
+	out, _, err := RenderTemplateWithSDKE(t, options, chartDir, releaseName, templateFiles)
+	require.NoError(t, err)
+	return out
+}
+
+// RenderTemplateWithSDKE renders the given chart in-process using the Helm SDK instead of shelling out to the helm
+// binary. It returns both the concatenated rendered manifests and a map of rendered file path to the list of
+// rendered YAML documents that came from that file, so callers that need to inspect individual templates don't
+// have to split the concatenated output themselves. The map is keyed by file, not by document, because a single
+// template file can render more than one `---`-separated document (e.g. a `range` over a list of ConfigMaps).
+// templateFiles are matched the same way as RenderTemplateE's `-s`/`-x` flag: as chart-relative paths (e.g.
+// "templates/foo.yaml"), not the chart-name-prefixed path ("<chart>/templates/foo.yaml") helm's rendered output
+// reports them under.
+//
+// Unlike RenderTemplateE, this does not require a helm binary on the test host, and chart load, template parse, and
+// values schema validation errors are returned as typed Go errors (via the underlying helm.sh/helm/v3 packages)
+// rather than parsed out of CLI stderr.
+//
+// Note: this renderer does not go through RenderTemplateE's helm-CLI argument building, so Options.Environment,
+// Options.StateValuesSet, and Options.Repositories are not honored here - a caller switching from RenderTemplateE
+// to this function gets ValuesFiles/SetValues/SetStrValues applied, but those three fields silently ignored.
+func RenderTemplateWithSDKE(t *testing.T, options *Options, chartDir string, releaseName string, templateFiles []string) (string, map[string][]string, error) {
+	if _, err := filepath.Abs(chartDir); err != nil {
+		return "", nil, gwErrors.WithStackTrace(err)
+	}
+	if !files.FileExists(chartDir) {
+		return "", nil, gwErrors.WithStackTrace(ChartNotFoundError{chartDir})
+	}
+
+	chrt, err := loader.Load(chartDir)
+	if err != nil {
+		return "", nil, gwErrors.WithStackTrace(err)
+	}
+
+	return renderLoadedChartWithSDKE(t, options, chrt, releaseName, templateFiles)
+}
+
+// RenderChartWithSDKE is the same as RenderTemplateWithSDKE, but takes an already-loaded *chart.Chart instead of a
+// chart directory. This is useful for tests that build a chart programmatically rather than reading it off disk.
+func RenderChartWithSDKE(t *testing.T, options *Options, chrt *chart.Chart, releaseName string, templateFiles []string) (string, map[string][]string, error) {
+	return renderLoadedChartWithSDKE(t, options, chrt, releaseName, templateFiles)
+}
+
+func renderLoadedChartWithSDKE(t *testing.T, options *Options, chrt *chart.Chart, releaseName string, templateFiles []string) (string, map[string][]string, error) {
+	if options == nil {
+		options = &Options{}
+	}
+
+	values, err := mergeSDKValuesE(t, options, chrt)
+	if err != nil {
+		return "", nil, err
+	}
+
+	actionConfig := new(action.Configuration)
+	install := action.NewInstall(actionConfig)
+	install.ClientOnly = true
+	install.DryRun = true
+	install.ReleaseName = releaseName
+	install.Replace = true
+	install.IncludeCRDs = true
+	install.Namespace = namespaceFromOptions(options)
+
+	rel, err := install.Run(chrt, values)
+	if err != nil {
+		return "", nil, gwErrors.WithStackTrace(err)
+	}
+
+	docs, err := splitRenderedManifestE(rel.Manifest, templateFiles)
+	if err != nil {
+		return "", nil, err
+	}
+
+	rendered := map[string][]string{}
+	var out bytes.Buffer
+	for _, doc := range docs {
+		rendered[doc.Path] = append(rendered[doc.Path], doc.Content)
+		fmt.Fprintf(&out, "---\n# Source: %s\n%s\n", doc.Path, doc.Content)
+	}
+
+	return out.String(), rendered, nil
+}
+
+// mergeSDKValuesE builds the values map that is handed to the Helm SDK install action, applying SetValues,
+// SetStrValues, and ValuesFiles in the same precedence order as RenderTemplateE applies them via the helm CLI.
+func mergeSDKValuesE(t *testing.T, options *Options, chrt *chart.Chart) (map[string]interface{}, error) {
+	base := map[string]interface{}{}
+
+	for _, valuesFile := range options.ValuesFiles {
+		fileValues, err := chartutil.ReadValuesFile(valuesFile)
+		if err != nil {
+			return nil, gwErrors.WithStackTrace(err)
+		}
+		base = mergeMaps(base, fileValues.AsMap())
+	}
+
+	for k, v := range options.SetValues {
+		if err := strvals.ParseInto(fmt.Sprintf("%s=%s", k, v), base); err != nil {
+			return nil, gwErrors.WithStackTrace(err)
+		}
+	}
+	for k, v := range options.SetStrValues {
+		if err := setNestedValue(base, k, v); err != nil {
+			return nil, gwErrors.WithStackTrace(err)
+		}
+	}
+
+	return base, nil
+}
+
+func namespaceFromOptions(options *Options) string {
+	if options.KubectlOptions != nil && options.KubectlOptions.Namespace != "" {
+		return options.KubectlOptions.Namespace
+	}
+	return "default"
+}
+
+// RenderedTemplateDoc is a single `---`-separated YAML document from a rendered chart, along with the source
+// template file it came from.
+type RenderedTemplateDoc struct {
+	Path    string
+	Content string
+}
+
+// splitRenderedManifestE splits a concatenated "helm install --dry-run" manifest on its `---` document boundaries
+// and returns one RenderedTemplateDoc per document, in the order helm rendered them. Helm only emits a
+// "# Source: <path>" comment on the first document of each template file, not on every document, so a template
+// that itself renders multiple `---`-separated documents (e.g. a `range` over a list of ConfigMaps) produces
+// several consecutive documents under the same source - those source-less documents are associated with the most
+// recently seen "# Source:" path rather than being dropped.
+//
+// If templateFiles is non-empty, only documents whose source path ends with one of templateFiles are included -
+// helm reports sources as "<chartName>/templates/foo.yaml", but callers pass the chart-relative
+// "templates/foo.yaml" (the same value RenderTemplateE's -s/-x flag takes), so an exact match would never hit.
+func splitRenderedManifestE(manifest string, templateFiles []string) ([]RenderedTemplateDoc, error) {
+	var docs []RenderedTemplateDoc
+	currentPath := ""
+
+	chunks := bytes.Split([]byte(manifest), []byte("---\n"))
+	for _, chunk := range chunks {
+		chunk = bytes.TrimSpace(chunk)
+		if len(chunk) == 0 {
+			continue
+		}
+
+		content := chunk
+		lines := bytes.SplitN(chunk, []byte("\n"), 2)
+		if bytes.HasPrefix(lines[0], []byte("# Source: ")) {
+			currentPath = string(bytes.TrimPrefix(lines[0], []byte("# Source: ")))
+			content = nil
+			if len(lines) == 2 {
+				content = lines[1]
+			}
+		}
+
+		if currentPath == "" {
+			// Malformed/unexpected output with no preceding "# Source:" comment at all - nothing to key it by.
+			continue
+		}
+		if len(bytes.TrimSpace(content)) == 0 {
+			continue
+		}
+		if len(templateFiles) > 0 && !matchesAnyTemplateFile(currentPath, templateFiles) {
+			continue
+		}
+
+		docs = append(docs, RenderedTemplateDoc{Path: currentPath, Content: string(content)})
+	}
+	return docs, nil
+}
+
+// matchesAnyTemplateFile reports whether sourcePath (e.g. "mychart/templates/foo.yaml") ends with one of
+// templateFiles (e.g. "templates/foo.yaml"), matching on path separators so "templates/foo.yaml" doesn't
+// incorrectly match "templates/other-foo.yaml".
+func matchesAnyTemplateFile(sourcePath string, templateFiles []string) bool {
+	for _, f := range templateFiles {
+		if sourcePath == f || strings.HasSuffix(sourcePath, "/"+f) {
+			return true
+		}
+	}
+	return false
+}
+
+func mergeMaps(dst, src map[string]interface{}) map[string]interface{} {
+	for k, v := range src {
+		if dstMap, ok := dst[k].(map[string]interface{}); ok {
+			if srcMap, ok := v.(map[string]interface{}); ok {
+				dst[k] = mergeMaps(dstMap, srcMap)
+				continue
+			}
+		}
+		dst[k] = v
+	}
+	return dst
+}
+
+// setNestedValue sets a dotted-path key (e.g. "a.b.c") to value inside dst, creating intermediate maps as needed,
+// mirroring how `helm template --set` addresses nested values.
+func setNestedValue(dst map[string]interface{}, key string, value interface{}) error {
+	parts := strings.Split(key, ".")
+	cur := dst
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := cur[part].(map[string]interface{})
+		if !ok {
+			next = map[string]interface{}{}
+			cur[part] = next
+		}
+		cur = next
+	}
+	cur[parts[len(parts)-1]] = value
+	return nil
+}
@@ -0,0 +1,142 @@
+package helm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetNestedValue(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		key      string
+		value    interface{}
+		starting map[string]interface{}
+		expected map[string]interface{}
+	}{
+		{
+			name:     "top level key",
+			key:      "foo",
+			value:    "bar",
+			starting: map[string]interface{}{},
+			expected: map[string]interface{}{"foo": "bar"},
+		},
+		{
+			name:     "nested key creates intermediate maps",
+			key:      "a.b.c",
+			value:    "x",
+			starting: map[string]interface{}{},
+			expected: map[string]interface{}{"a": map[string]interface{}{"b": map[string]interface{}{"c": "x"}}},
+		},
+		{
+			name:  "nested key preserves sibling",
+			key:   "a.b.d",
+			value: "y",
+			starting: map[string]interface{}{
+				"a": map[string]interface{}{"b": map[string]interface{}{"c": "x"}},
+			},
+			expected: map[string]interface{}{
+				"a": map[string]interface{}{"b": map[string]interface{}{"c": "x", "d": "y"}},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.NoError(t, setNestedValue(testCase.starting, testCase.key, testCase.value))
+			assert.Equal(t, testCase.expected, testCase.starting)
+		})
+	}
+}
+
+func TestMergeMaps(t *testing.T) {
+	t.Parallel()
+
+	dst := map[string]interface{}{
+		"a": map[string]interface{}{"x": 1, "y": 2},
+		"b": "unchanged",
+	}
+	src := map[string]interface{}{
+		"a": map[string]interface{}{"y": 20, "z": 3},
+		"c": "new",
+	}
+
+	merged := mergeMaps(dst, src)
+
+	assert.Equal(t, map[string]interface{}{
+		"a": map[string]interface{}{"x": 1, "y": 20, "z": 3},
+		"b": "unchanged",
+		"c": "new",
+	}, merged)
+}
+
+func TestSplitRenderedManifestE(t *testing.T) {
+	t.Parallel()
+
+	manifest := "---\n# Source: mychart/templates/deployment.yaml\nkind: Deployment\n" +
+		"---\n# Source: mychart/templates/service.yaml\nkind: Service\n"
+
+	testCases := []struct {
+		name          string
+		templateFiles []string
+		expectedPaths []string
+	}{
+		{
+			name:          "no filter returns all documents",
+			templateFiles: nil,
+			expectedPaths: []string{"mychart/templates/deployment.yaml", "mychart/templates/service.yaml"},
+		},
+		{
+			name:          "chart-relative templateFiles path matches the chart-prefixed source",
+			templateFiles: []string{"templates/deployment.yaml"},
+			expectedPaths: []string{"mychart/templates/deployment.yaml"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			docs, err := splitRenderedManifestE(manifest, testCase.templateFiles)
+			require.NoError(t, err)
+
+			actualPaths := make([]string, 0, len(docs))
+			for _, doc := range docs {
+				actualPaths = append(actualPaths, doc.Path)
+			}
+			assert.ElementsMatch(t, testCase.expectedPaths, actualPaths)
+		})
+	}
+}
+
+func TestSplitRenderedManifestEMultipleDocsPerSourceFile(t *testing.T) {
+	t.Parallel()
+
+	// Helm only emits "# Source:" on the first document of a template file - a template that itself renders
+	// multiple `---`-separated documents (e.g. `range`ing over a list of ConfigMaps) produces consecutive
+	// source-less documents that must still be attributed to that file.
+	manifest := "---\n# Source: mychart/templates/configmaps.yaml\nkind: ConfigMap\nname: one\n" +
+		"---\nkind: ConfigMap\nname: two\n" +
+		"---\nkind: ConfigMap\nname: three\n" +
+		"---\n# Source: mychart/templates/service.yaml\nkind: Service\n"
+
+	docs, err := splitRenderedManifestE(manifest, nil)
+	require.NoError(t, err)
+	require.Len(t, docs, 4)
+
+	assert.Equal(t, "mychart/templates/configmaps.yaml", docs[0].Path)
+	assert.Equal(t, "mychart/templates/configmaps.yaml", docs[1].Path)
+	assert.Equal(t, "mychart/templates/configmaps.yaml", docs[2].Path)
+	assert.Equal(t, "mychart/templates/service.yaml", docs[3].Path)
+
+	assert.Contains(t, docs[0].Content, "name: one")
+	assert.Contains(t, docs[1].Content, "name: two")
+	assert.Contains(t, docs[2].Content, "name: three")
+}